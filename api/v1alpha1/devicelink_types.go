@@ -0,0 +1,142 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RetryPolicy overrides the default exponential backoff used when a
+// DeviceLink fails to connect to, or send data through, its adaptor.
+// Durations are parsed with time.ParseDuration; an override that fails to
+// parse, or is left empty, falls back to the limb-wide default for that field.
+type RetryPolicy struct {
+	// InitialInterval is the backoff before the first retry, e.g. "1s".
+	// +optional
+	InitialInterval string `json:"initialInterval,omitempty"`
+	// MaxInterval caps how large the backoff is allowed to grow, e.g. "1m".
+	// +optional
+	MaxInterval string `json:"maxInterval,omitempty"`
+	// Multiplier scales the interval after every attempt.
+	// +optional
+	Multiplier float64 `json:"multiplier,omitempty"`
+	// Jitter randomises each interval by up to this fraction.
+	// +optional
+	Jitter float64 `json:"jitter,omitempty"`
+	// MaxElapsedTime bounds the total time spent retrying before the
+	// DeviceLink is left in a failed state awaiting an external trigger,
+	// e.g. "15m". A zero duration such as "0s" means retry indefinitely.
+	// +optional
+	MaxElapsedTime string `json:"maxElapsedTime,omitempty"`
+}
+
+// DeviceAdaptor identifies the adaptor plugin that a DeviceLink talks to.
+type DeviceAdaptor struct {
+	// Node is the name of the node the adaptor runs on.
+	Node string `json:"node,omitempty"`
+	// Name is the name the adaptor plugin is registered under.
+	Name string `json:"name"`
+	// Version is the version of the adaptor plugin to connect to. Changing
+	// only this on an already-connected DeviceLink, to a version that is
+	// already registered, keeps the existing session rather than tearing it
+	// down; a version that isn't registered disconnects the DeviceLink.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Parameters carries the adaptor-specific connection configuration.
+	// +optional
+	Parameters *runtime.RawExtension `json:"parameters,omitempty"`
+	// RetryPolicy overrides the default backoff applied when connecting to,
+	// or sending data through, this adaptor fails.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// DeviceTemplateSpec describes how to construct the device instance a
+// DeviceLink manages.
+type DeviceTemplateSpec struct {
+	// Labels are copied onto the constructed device.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Spec is the device-type-specific spec, as raw JSON.
+	Spec runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// DeviceLinkSpec defines the desired state of DeviceLink.
+type DeviceLinkSpec struct {
+	// Adaptor references the adaptor plugin this DeviceLink connects through.
+	Adaptor DeviceAdaptor `json:"adaptor"`
+	// Template instantiates the device this DeviceLink manages.
+	Template DeviceTemplateSpec `json:"template"`
+}
+
+// DeviceLinkConditionType is a valid value of DeviceLinkCondition.Type.
+type DeviceLinkConditionType string
+
+// DeviceLinkCondition describes a stage of the DeviceLink reconciling process.
+type DeviceLinkCondition struct {
+	// Type of this condition.
+	Type DeviceLinkConditionType `json:"type"`
+	// Status of this condition, one of True, False, Unknown.
+	Status metav1.ConditionStatus `json:"status"`
+	// Reason is a one-word CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about the transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastUpdateTime is the last time this condition was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned between statuses.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// DeviceLinkStatus defines the observed state of DeviceLink.
+type DeviceLinkStatus struct {
+	// NodeName is the node this DeviceLink has been scheduled onto.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+	// AdaptorName is the adaptor name last observed to back this DeviceLink.
+	// +optional
+	AdaptorName string `json:"adaptorName,omitempty"`
+	// Adaptor mirrors the adaptor configuration currently in effect.
+	// +optional
+	Adaptor DeviceAdaptor `json:"adaptor,omitempty"`
+	// Model is the GVK of the device instance this DeviceLink manages.
+	// +optional
+	Model metav1.TypeMeta `json:"model,omitempty"`
+	// MasterTerm is a monotonically increasing counter bumped every time a
+	// limb takes over mastership of this DeviceLink, e.g. after it moves to
+	// a different node. A limb operating under a term older than the one
+	// recorded here must stop touching the adaptor connection.
+	// +optional
+	MasterTerm int64 `json:"masterTerm,omitempty"`
+	// Conditions is the list of stages this DeviceLink has gone through.
+	// +optional
+	Conditions []DeviceLinkCondition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// DeviceLink is the Schema for the devicelinks API.
+type DeviceLink struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeviceLinkSpec   `json:"spec,omitempty"`
+	Status DeviceLinkStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DeviceLinkList contains a list of DeviceLink.
+type DeviceLinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceLink `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DeviceLink{}, &DeviceLinkList{})
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"bytes"
 	"context"
+	"errors"
 	"reflect"
 	"time"
 
@@ -13,9 +14,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
 	"github.com/rancher/octopus/pkg/limb/index"
@@ -42,14 +47,39 @@ type DeviceLinkReconciler struct {
 
 	SuctionCup suctioncup.Neurons
 	NodeName   string
+	TermStore  TermStore
+
+	RetryPolicy RetryPolicy
+	Retries     *RetryTracker
+
+	// Context is the manager-scoped context, cancelled on SIGTERM; each
+	// Reconcile derives a bounded child context from it so a hung adaptor
+	// can no longer pin a worker, or block graceful manager shutdown.
+	Context context.Context
+	// ReconcileTimeout bounds how long a single Reconcile call, and the
+	// SuctionCup calls it makes, are allowed to run. Defaults to 30s.
+	ReconcileTimeout time.Duration
 }
 
+// DefaultReconcileTimeout is used when ReconcileTimeout is left unset.
+const DefaultReconcileTimeout = 30 * time.Second
+
 // +kubebuilder:rbac:groups=edge.cattle.io,resources=devicelinks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=edge.cattle.io,resources=devicelinks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	var ctx = context.Background()
+	var reconcileTimeout = r.ReconcileTimeout
+	if reconcileTimeout <= 0 {
+		reconcileTimeout = DefaultReconcileTimeout
+	}
+	var baseCtx = r.Context
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(baseCtx, reconcileTimeout)
+	defer cancel()
+
 	var log = r.Log.WithValues("deviceLink", req.NamespacedName)
 	var metricsRecorder = metrics.GetLimbMetricsRecorder()
 
@@ -67,17 +97,35 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 	// rejects if not the requested node
 	if link.Status.NodeName != r.NodeName {
 		// NB(thxCode) disconnects the link to avoid connection leak when the requested node has been changed
-		if exist := r.SuctionCup.Disconnect(&link); exist {
-			metricsRecorder.DecreaseConnections(link.Status.AdaptorName)
+		if exist := r.SuctionCup.Disconnect(ctx, &link); exist {
+			metricsRecorder.DecreaseConnections(link.Status.AdaptorName, link.Status.Adaptor.Version)
 		}
+		r.TermStore.Release(req.NamespacedName)
+		r.Retries.Reset(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
+	// acquires (or recalls) the mastership term for this DeviceLink; this is
+	// a no-op once a term has already been recorded for it
+	term, err := r.TermStore.Acquire(ctx, &link)
+	if err != nil {
+		if errors.Is(err, ErrStaleMastership) {
+			log.Info("Abandoning DeviceLink, another limb has since won mastership of it")
+			if exist := r.SuctionCup.Disconnect(ctx, &link); exist {
+				metricsRecorder.DecreaseConnections(link.Status.AdaptorName, link.Status.Adaptor.Version)
+			}
+			r.Retries.Reset(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Unable to acquire mastership term of DeviceLink")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// rejects if the conditions are not met
 	if devicelink.GetModelExistedStatus(&link.Status) != metav1.ConditionTrue {
 		// NB(thxCode) disconnects the link to avoid connection leak when the model has been changed or removed
-		if exist := r.SuctionCup.Disconnect(&link); exist {
-			metricsRecorder.DecreaseConnections(link.Status.AdaptorName)
+		if exist := r.SuctionCup.Disconnect(ctx, &link); exist {
+			metricsRecorder.DecreaseConnections(link.Status.AdaptorName, link.Status.Adaptor.Version)
 		}
 		return ctrl.Result{}, nil
 	}
@@ -88,9 +136,11 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		}
 
 		// disconnects
-		if exist := r.SuctionCup.Disconnect(&link); exist {
-			metricsRecorder.DecreaseConnections(link.Status.AdaptorName)
+		if exist := r.SuctionCup.Disconnect(ctx, &link); exist {
+			metricsRecorder.DecreaseConnections(link.Status.AdaptorName, link.Status.Adaptor.Version)
 		}
+		r.TermStore.Release(req.NamespacedName)
+		r.Retries.Reset(req.NamespacedName)
 
 		// removes finalizer
 		link.Finalizers = collection.StringSliceRemove(link.Finalizers, ReconcilingDeviceLink)
@@ -115,9 +165,12 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 	// validates adaptor existing or not
 	switch devicelink.GetAdaptorExistedStatus(&link.Status) {
 	case metav1.ConditionFalse:
-		if r.SuctionCup.ExistAdaptor(link.Spec.Adaptor.Name) ||
+		if r.SuctionCup.ExistAdaptor(ctx, link.Spec.Adaptor.Name, link.Spec.Adaptor.Version) ||
 			link.Status.AdaptorName != link.Spec.Adaptor.Name ||
 			compareAdaptorParameters(link.Spec.Adaptor, link.Status.Adaptor) {
+			if r.abandonStaleTerm(ctx, req, &link, term, metricsRecorder, log) {
+				return ctrl.Result{}, nil
+			}
 			devicelink.ToCheckAdaptorExisted(&link.Status)
 			if err := r.Status().Update(ctx, &link); err != nil {
 				log.Error(err, "Unable to change the status of DeviceLink")
@@ -126,12 +179,37 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		}
 		return ctrl.Result{}, nil
 	case metav1.ConditionTrue:
-		if !r.SuctionCup.ExistAdaptor(link.Spec.Adaptor.Name) ||
-			link.Status.AdaptorName != link.Spec.Adaptor.Name ||
-			compareAdaptorParameters(link.Spec.Adaptor, link.Status.Adaptor) {
+		var nameOrParametersChanged = link.Status.AdaptorName != link.Spec.Adaptor.Name ||
+			compareAdaptorParameters(link.Spec.Adaptor, link.Status.Adaptor)
+		var versionChanged = link.Status.Adaptor.Version != link.Spec.Adaptor.Version
+
+		if !nameOrParametersChanged && versionChanged &&
+			r.SuctionCup.ExistAdaptor(ctx, link.Spec.Adaptor.Name, link.Spec.Adaptor.Version) {
+			if r.abandonStaleTerm(ctx, req, &link, term, metricsRecorder, log) {
+				return ctrl.Result{}, nil
+			}
+			// NB(thxCode) only the pinned adaptor version moved, and the new
+			// version is already registered: mirror how the device manager
+			// retains prior versioned installations, keep the existing
+			// session alive instead of tearing it down so an in-flight
+			// device isn't disturbed by a rolling upgrade of the adaptor.
+			devicelink.SuccessOnAdaptorExisted(&link.Status)
+			link.Status.Adaptor.Version = link.Spec.Adaptor.Version
+			r.Eventf(&link, "Normal", "AdaptorVersionChanged", "adaptor version pinned to %q", link.Spec.Adaptor.Version)
+			if err := r.Status().Update(ctx, &link); err != nil {
+				log.Error(err, "Unable to change the status of DeviceLink")
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+
+		if !r.SuctionCup.ExistAdaptor(ctx, link.Spec.Adaptor.Name, link.Spec.Adaptor.Version) || nameOrParametersChanged {
+			if r.abandonStaleTerm(ctx, req, &link, term, metricsRecorder, log) {
+				return ctrl.Result{}, nil
+			}
 			// NB(thxCode) disconnects the link to avoid connection leak when the requested adaptor has been changed
-			if exist := r.SuctionCup.Disconnect(&link); exist {
-				metricsRecorder.DecreaseConnections(link.Status.AdaptorName)
+			if exist := r.SuctionCup.Disconnect(ctx, &link); exist {
+				metricsRecorder.DecreaseConnections(link.Status.AdaptorName, link.Status.Adaptor.Version)
 			}
 			devicelink.ToCheckAdaptorExisted(&link.Status)
 			if err := r.Status().Update(ctx, &link); err != nil {
@@ -141,7 +219,10 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 			return ctrl.Result{}, nil
 		}
 	default:
-		if r.SuctionCup.ExistAdaptor(link.Spec.Adaptor.Name) {
+		if r.abandonStaleTerm(ctx, req, &link, term, metricsRecorder, log) {
+			return ctrl.Result{}, nil
+		}
+		if r.SuctionCup.ExistAdaptor(ctx, link.Spec.Adaptor.Name, link.Spec.Adaptor.Version) {
 			devicelink.SuccessOnAdaptorExisted(&link.Status)
 		} else {
 			devicelink.FailOnAdaptorExisted(&link.Status, "the adaptor isn't existed")
@@ -149,6 +230,7 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 
 		link.Status.AdaptorName = link.Spec.Adaptor.Name
 		link.Status.Adaptor.Parameters = link.Spec.Adaptor.Parameters
+		link.Status.Adaptor.Version = link.Spec.Adaptor.Version
 		if err := r.Status().Update(ctx, &link); err != nil {
 			log.Error(err, "Unable to change the status of DeviceLink")
 			return ctrl.Result{Requeue: true}, nil
@@ -166,6 +248,9 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		var err error
 		device, err = model.NewInstanceOfTypeMeta(link.Status.Model)
 		if err != nil {
+			if r.abandonStaleTerm(ctx, req, &link, term, metricsRecorder, log) {
+				return ctrl.Result{}, nil
+			}
 			devicelink.FailOnDeviceCreated(&link.Status, "unable to update device from template")
 			r.Eventf(&link, "Warning", "FailedCreated", "cannot update device from template: %v", err)
 			if err := r.Status().Update(ctx, &link); err != nil {
@@ -181,6 +266,9 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 			}
 		}
 		if !object.IsActivating(&device) {
+			if r.abandonStaleTerm(ctx, req, &link, term, metricsRecorder, log) {
+				return ctrl.Result{}, nil
+			}
 			devicelink.ToCheckDeviceCreated(&link.Status)
 			if err := r.Status().Update(ctx, &link); err != nil {
 				log.Error(err, "Unable to change the status of DeviceLink")
@@ -192,6 +280,9 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		// updates device
 		updated, err := updateDevice(&link, &device)
 		if err != nil {
+			if r.abandonStaleTerm(ctx, req, &link, term, metricsRecorder, log) {
+				return ctrl.Result{}, nil
+			}
 			devicelink.FailOnDeviceCreated(&link.Status, "unable to update device from template")
 			r.Eventf(&link, "Warning", "FailedCreated", "cannot update device from template: %v", err)
 			if err := r.Status().Update(ctx, &link); err != nil {
@@ -207,6 +298,10 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 			}
 		}
 	default:
+		if r.abandonStaleTerm(ctx, req, &link, term, metricsRecorder, log) {
+			return ctrl.Result{}, nil
+		}
+
 		// creates device
 		if device, err := constructDevice(&link, r.Scheme); err != nil {
 			devicelink.FailOnDeviceCreated(&link.Status, "unable to construct device from template")
@@ -235,20 +330,30 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, nil
 	}
 
+	if r.abandonStaleTerm(ctx, req, &link, term, metricsRecorder, log) {
+		return ctrl.Result{}, nil
+	}
+
 	// validates device connected or not
 	switch devicelink.GetDeviceConnectedStatus(&link.Status) {
 	case metav1.ConditionFalse:
-		// NB(thxCode) could not send any data to unhealthy connection,
-		// this status changes maybe can drive by suction cup.
-		return ctrl.Result{}, nil
+		// NB(thxCode) the connection is unhealthy; rather than waiting
+		// indefinitely for a chunk0-1 ResponseEvent (there may be no live
+		// session to produce one, e.g. the initial Connect never succeeded),
+		// wait out the next-retry time of the resolved RetryPolicy, then
+		// retry to (re)connect.
+		if due, wait := r.Retries.Due(req.NamespacedName); !due {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		return r.connect(ctx, req, &link, term, metricsRecorder, log)
 	case metav1.ConditionTrue:
 		sendStartTS := time.Now()
 		defer func() {
-			metricsRecorder.ObserveSendLatency(link.Status.AdaptorName, time.Since(sendStartTS))
+			metricsRecorder.ObserveSendLatency(link.Status.AdaptorName, link.Status.Adaptor.Version, time.Since(sendStartTS))
 		}()
 
-		if err := r.SuctionCup.Send(&device, &link); err != nil {
-			metricsRecorder.IncreaseSendErrors(link.Status.AdaptorName)
+		if err := r.SuctionCup.Send(ctx, &device, &link, term); err != nil {
+			metricsRecorder.IncreaseSendErrors(link.Status.AdaptorName, link.Status.Adaptor.Version)
 
 			devicelink.FailOnDeviceConnected(&link.Status, "cannot send data to adaptor")
 			r.Eventf(&link, "Warning", "FailedSent", "cannot send data to adaptor: %v", err)
@@ -257,29 +362,91 @@ func (r *DeviceLinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 				log.Error(err, "Unable to change the status of DeviceLink")
 				return ctrl.Result{Requeue: true}, nil
 			}
+			return r.backoff(ctx, &link, log)
 		}
+		r.Retries.Reset(req.NamespacedName)
 		return ctrl.Result{}, nil
 	default:
-		if overwrite, err := r.SuctionCup.Connect(&link); err != nil {
-			metricsRecorder.IncreaseConnectErrors(link.Status.AdaptorName)
+		return r.connect(ctx, req, &link, term, metricsRecorder, log)
+	}
+}
 
-			devicelink.FailOnDeviceConnected(&link.Status, "unable to connect to adaptor")
-			r.Eventf(&link, "Warning", "FailedConnected", "cannot connect to adaptor: %v", err)
-		} else {
-			if !overwrite {
-				metricsRecorder.IncreaseConnections(link.Status.AdaptorName)
-			}
+// abandonStaleTerm reports whether the in-memory mastership term for this
+// DeviceLink no longer matches the term this Reconcile acquired, disconnecting
+// and forgetting the DeviceLink's term/retry state if so. TermStore.Acquire
+// only catches staleness that existed at the start of Reconcile; a competing
+// limb can still win mastership mid-reconcile, so every Status().Update,
+// Connect, or Send call that follows Acquire must be preceded by this check
+// rather than relying on the single check done once at the top.
+func (r *DeviceLinkReconciler) abandonStaleTerm(ctx context.Context, req ctrl.Request, link *edgev1alpha1.DeviceLink, term int64, metricsRecorder *metrics.LimbMetricsRecorder, log logr.Logger) bool {
+	if current, ok := r.TermStore.Current(req.NamespacedName); ok && current == term {
+		return false
+	}
+	log.Info("Abandoning DeviceLink, mastership term is stale")
+	if exist := r.SuctionCup.Disconnect(ctx, link); exist {
+		metricsRecorder.DecreaseConnections(link.Status.AdaptorName, link.Status.Adaptor.Version)
+	}
+	r.TermStore.Release(req.NamespacedName)
+	r.Retries.Reset(req.NamespacedName)
+	return true
+}
 
-			devicelink.SuccessOnDeviceConnected(&link.Status)
-			r.Eventf(&link, "Normal", "Connected", "connected to adaptor")
+// connect attempts to (re)establish the adaptor connection for the given
+// DeviceLink, persists the outcome, and falls back to backoff when it fails.
+func (r *DeviceLinkReconciler) connect(ctx context.Context, req ctrl.Request, link *edgev1alpha1.DeviceLink, term int64, metricsRecorder *metrics.LimbMetricsRecorder, log logr.Logger) (ctrl.Result, error) {
+	overwrite, connectErr := r.SuctionCup.Connect(ctx, link, term)
+	if connectErr != nil {
+		metricsRecorder.IncreaseConnectErrors(link.Status.AdaptorName, link.Status.Adaptor.Version)
+
+		devicelink.FailOnDeviceConnected(&link.Status, "unable to connect to adaptor")
+		r.Eventf(link, "Warning", "FailedConnected", "cannot connect to adaptor: %v", connectErr)
+	} else {
+		if !overwrite {
+			metricsRecorder.IncreaseConnections(link.Status.AdaptorName, link.Status.Adaptor.Version)
 		}
 
-		if err := r.Status().Update(ctx, &link); err != nil {
+		devicelink.SuccessOnDeviceConnected(&link.Status)
+		devicelink.ClearDeviceConnectedBackoff(&link.Status, "Connected")
+		r.Eventf(link, "Normal", "Connected", "connected to adaptor")
+		r.Retries.Reset(req.NamespacedName)
+	}
+
+	if err := r.Status().Update(ctx, link); err != nil {
+		log.Error(err, "Unable to change the status of DeviceLink")
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if connectErr != nil {
+		return r.backoff(ctx, link, log)
+	}
+	return ctrl.Result{}, nil
+}
+
+// backoff records another failed adaptor attempt for the given DeviceLink
+// and returns a RequeueAfter matching the resolved RetryPolicy, surfacing
+// the resulting wait as a DeviceConnectedBackoff status condition. When the
+// policy's MaxElapsedTime has been exceeded, it emits a Warning event
+// instead so an operator can intervene.
+func (r *DeviceLinkReconciler) backoff(ctx context.Context, link *edgev1alpha1.DeviceLink, log logr.Logger) (ctrl.Result, error) {
+	var name = types.NamespacedName{Namespace: link.Namespace, Name: link.Name}
+	var policy = resolveRetryPolicy(r.RetryPolicy, link.Spec.Adaptor.RetryPolicy)
+
+	wait, nextRetryTime, exceeded := r.Retries.Next(name, policy)
+	if exceeded {
+		r.Eventf(link, "Warning", "BackoffExceeded", "adaptor connection has been failing for more than %s, giving up automatic retries", policy.MaxElapsedTime)
+		devicelink.ClearDeviceConnectedBackoff(&link.Status, "Exceeded")
+		if err := r.Status().Update(ctx, link); err != nil {
 			log.Error(err, "Unable to change the status of DeviceLink")
 			return ctrl.Result{Requeue: true}, nil
 		}
 		return ctrl.Result{}, nil
 	}
+
+	devicelink.ToDeviceConnectedBackoff(&link.Status, nextRetryTime)
+	if err := r.Status().Update(ctx, link); err != nil {
+		log.Error(err, "Unable to change the status of DeviceLink")
+		return ctrl.Result{Requeue: true}, nil
+	}
+	return ctrl.Result{RequeueAfter: wait}, nil
 }
 
 func (r *DeviceLinkReconciler) SetupWithManager(ctrlMgr ctrl.Manager, suctionCupMgr suctioncup.Manager) error {
@@ -300,9 +467,28 @@ func (r *DeviceLinkReconciler) SetupWithManager(ctrlMgr ctrl.Manager, suctionCup
 		Named("limb_dl").
 		For(&edgev1alpha1.DeviceLink{}).
 		WithEventFilter(predicate.DeviceLinkChangedPredicate{NodeName: r.NodeName}).
+		Watches(r.responseEventSource(), &handler.EnqueueRequestForObject{}).
 		Complete(r)
 }
 
+// responseEventSource bridges the suctioncup.Session goroutines to the
+// controller's workqueue: every adaptor-initiated event (Connected,
+// Disconnected, DataReceived, Error) requeues the DeviceLink it belongs to,
+// so the controller can react without polling.
+func (r *DeviceLinkReconciler) responseEventSource() *source.Channel {
+	var ch = make(chan event.GenericEvent)
+	go func() {
+		defer close(ch)
+		for ev := range r.SuctionCup.ResponseEvents() {
+			var link edgev1alpha1.DeviceLink
+			link.Namespace = ev.NamespacedName.Namespace
+			link.Name = ev.NamespacedName.Name
+			ch <- event.GenericEvent{Object: &link}
+		}
+	}()
+	return &source.Channel{Source: ch}
+}
+
 func updateDevice(from *edgev1alpha1.DeviceLink, target *unstructured.Unstructured) (updated bool, err error) {
 	var original = target.DeepCopy()
 
@@ -372,6 +558,9 @@ func markDevice(link *edgev1alpha1.DeviceLink, deviceAnnotations map[string]stri
 	var deviceAdaptor = link.Spec.Adaptor
 	deviceAnnotations["edge.cattle.io/adaptor-node"] = deviceAdaptor.Node
 	deviceAnnotations["edge.cattle.io/adaptor-name"] = deviceAdaptor.Name
+	if deviceAdaptor.Version != "" {
+		deviceAnnotations["edge.cattle.io/adaptor-version"] = deviceAdaptor.Version
+	}
 	if deviceAdaptor.Parameters != nil {
 		deviceAnnotations["edge.cattle.io/adaptor-parameters"] = string(deviceAdaptor.Parameters.Raw)
 	}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+// RetryPolicy configures the exponential backoff applied to a DeviceLink
+// whose adaptor Connect/Send keeps failing.
+type RetryPolicy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Jitter          float64
+	MaxElapsedTime  time.Duration
+}
+
+// DefaultRetryPolicy is the base policy used when DeviceLinkReconciler.RetryPolicy
+// is left unset; no limb flag sets it.
+var DefaultRetryPolicy = RetryPolicy{
+	InitialInterval: time.Second,
+	MaxInterval:     time.Minute,
+	Multiplier:      2,
+	Jitter:          0.1,
+	MaxElapsedTime:  15 * time.Minute,
+}
+
+// NextInterval returns the backoff duration for the given (zero-based)
+// attempt number, capped at MaxInterval and randomised by Jitter.
+func (p RetryPolicy) NextInterval(attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+
+	var interval = float64(p.InitialInterval)
+	for i := 0; i < attempt; i++ {
+		interval *= p.Multiplier
+	}
+	if p.MaxInterval > 0 && interval > float64(p.MaxInterval) {
+		interval = float64(p.MaxInterval)
+	}
+	if p.Jitter > 0 {
+		interval *= 1 + p.Jitter*(rand.Float64()*2-1)
+	}
+	if interval < 0 {
+		interval = 0
+	}
+	return time.Duration(interval)
+}
+
+// resolveRetryPolicy returns the per-adaptor retryPolicy override if the
+// DeviceLink sets one, falling back to the limb-wide default otherwise. A
+// zero-value def (DeviceLinkReconciler.RetryPolicy left unset by whatever
+// wires it up) falls back to DefaultRetryPolicy, the same way ReconcileTimeout
+// self-guards against being left unset.
+func resolveRetryPolicy(def RetryPolicy, override *edgev1alpha1.RetryPolicy) RetryPolicy {
+	if def.InitialInterval <= 0 {
+		def = DefaultRetryPolicy
+	}
+
+	if override == nil {
+		return def
+	}
+
+	var policy = def
+	if d, err := time.ParseDuration(override.InitialInterval); err == nil {
+		policy.InitialInterval = d
+	}
+	if d, err := time.ParseDuration(override.MaxInterval); err == nil {
+		policy.MaxInterval = d
+	}
+	if override.Multiplier > 0 {
+		policy.Multiplier = override.Multiplier
+	}
+	if override.Jitter > 0 {
+		policy.Jitter = override.Jitter
+	}
+	if d, err := time.ParseDuration(override.MaxElapsedTime); err == nil {
+		policy.MaxElapsedTime = d
+	}
+	return policy
+}
+
+// retryState is the in-memory backoff bookkeeping for a single DeviceLink.
+type retryState struct {
+	attempt      int
+	firstAttempt time.Time
+	nextRetry    time.Time
+}
+
+// RetryTracker records the attempt count and next-retry timestamp per
+// DeviceLink. It is guarded by the caller's mastership term: a limb must
+// call Reset once it has lost mastership so a later limb starts from a
+// clean slate instead of inheriting a stale backoff.
+type RetryTracker struct {
+	mu     sync.Mutex
+	states map[types.NamespacedName]*retryState
+}
+
+// NewRetryTracker returns an empty RetryTracker.
+func NewRetryTracker() *RetryTracker {
+	return &RetryTracker{states: make(map[types.NamespacedName]*retryState)}
+}
+
+// Next records another failed attempt for the given DeviceLink and returns
+// how long to wait before retrying. exceeded is true once policy.MaxElapsedTime
+// has passed since the first attempt in the current run of failures.
+func (t *RetryTracker) Next(name types.NamespacedName, policy RetryPolicy) (wait time.Duration, nextRetryTime time.Time, exceeded bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var now = time.Now()
+	var st, ok = t.states[name]
+	if !ok {
+		st = &retryState{firstAttempt: now}
+		t.states[name] = st
+	}
+
+	if policy.MaxElapsedTime > 0 && now.Sub(st.firstAttempt) > policy.MaxElapsedTime {
+		return 0, time.Time{}, true
+	}
+
+	wait = policy.NextInterval(st.attempt)
+	st.attempt++
+	st.nextRetry = now.Add(wait)
+	return wait, st.nextRetry, false
+}
+
+// Reset forgets the backoff state for the given DeviceLink, e.g. after a
+// successful Connect/Send or after mastership is lost.
+func (t *RetryTracker) Reset(name types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.states, name)
+}
+
+// Due reports whether the given DeviceLink is allowed to retry now. If not,
+// wait is how much longer the caller should requeue for. A DeviceLink with
+// no recorded state yet is always due, since it hasn't failed before.
+func (t *RetryTracker) Due(name types.NamespacedName) (due bool, wait time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var st, ok = t.states[name]
+	if !ok {
+		return true, 0
+	}
+
+	var now = time.Now()
+	if !now.Before(st.nextRetry) {
+		return true, 0
+	}
+	return false, st.nextRetry.Sub(now)
+}
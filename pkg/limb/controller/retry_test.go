@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+// TestRetryPolicy_NextInterval_Growth verifies that NextInterval grows
+// geometrically by Multiplier and caps at MaxInterval.
+func TestRetryPolicy_NextInterval_Growth(t *testing.T) {
+	var policy = RetryPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+	}
+
+	var cases = []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{4, 10 * time.Second}, // capped
+		{5, 10 * time.Second}, // capped
+	}
+	for _, c := range cases {
+		if got := policy.NextInterval(c.attempt); got != c.expected {
+			t.Fatalf("attempt %d: expected %s, got %s", c.attempt, c.expected, got)
+		}
+	}
+}
+
+// TestRetryPolicy_NextInterval_NegativeAttempt verifies a negative attempt
+// number is treated as the first attempt rather than underflowing.
+func TestRetryPolicy_NextInterval_NegativeAttempt(t *testing.T) {
+	var policy = RetryPolicy{InitialInterval: time.Second, MaxInterval: time.Minute, Multiplier: 2}
+	if got := policy.NextInterval(-1); got != time.Second {
+		t.Fatalf("expected a negative attempt to behave like attempt 0, got %s", got)
+	}
+}
+
+// TestRetryPolicy_NextInterval_JitterBounds verifies Jitter randomises the
+// interval within the documented +/- fraction, never pushing it negative.
+func TestRetryPolicy_NextInterval_JitterBounds(t *testing.T) {
+	var policy = RetryPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Minute,
+		Multiplier:      1,
+		Jitter:          0.5,
+	}
+
+	var lower = time.Duration(float64(time.Second) * 0.5)
+	var upper = time.Duration(float64(time.Second) * 1.5)
+	for i := 0; i < 100; i++ {
+		var got = policy.NextInterval(0)
+		if got < lower || got > upper {
+			t.Fatalf("expected interval within [%s, %s], got %s", lower, upper, got)
+		}
+	}
+}
+
+// TestResolveRetryPolicy_ZeroDefFallsBackToDefault verifies that a zero-value
+// def (DeviceLinkReconciler.RetryPolicy left unset) resolves to
+// DefaultRetryPolicy instead of producing a policy whose InitialInterval is
+// zero, which would make NextInterval return 0 for every attempt.
+func TestResolveRetryPolicy_ZeroDefFallsBackToDefault(t *testing.T) {
+	var got = resolveRetryPolicy(RetryPolicy{}, nil)
+	if got != DefaultRetryPolicy {
+		t.Fatalf("expected a zero-value def to resolve to DefaultRetryPolicy, got %+v", got)
+	}
+}
+
+// TestResolveRetryPolicy_Override verifies that a per-adaptor override
+// replaces only the fields it sets, parsed from their string/duration form,
+// and otherwise falls back to def.
+func TestResolveRetryPolicy_Override(t *testing.T) {
+	var def = RetryPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Minute,
+		Multiplier:      2,
+		Jitter:          0.1,
+		MaxElapsedTime:  15 * time.Minute,
+	}
+
+	var override = &edgev1alpha1.RetryPolicy{
+		InitialInterval: "5s",
+		// MaxInterval left empty: falls back to def
+		Multiplier: 3,
+		// Jitter left zero: falls back to def
+		MaxElapsedTime: "1h",
+	}
+
+	var got = resolveRetryPolicy(def, override)
+	var want = RetryPolicy{
+		InitialInterval: 5 * time.Second,
+		MaxInterval:     time.Minute,
+		Multiplier:      3,
+		Jitter:          0.1,
+		MaxElapsedTime:  time.Hour,
+	}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+// TestResolveRetryPolicy_InvalidOverrideDurationIgnored verifies that an
+// override duration which fails to parse is ignored rather than zeroing out
+// the corresponding field.
+func TestResolveRetryPolicy_InvalidOverrideDurationIgnored(t *testing.T) {
+	var def = RetryPolicy{InitialInterval: time.Second, MaxInterval: time.Minute, Multiplier: 2}
+	var override = &edgev1alpha1.RetryPolicy{InitialInterval: "not-a-duration"}
+
+	var got = resolveRetryPolicy(def, override)
+	if got.InitialInterval != time.Second {
+		t.Fatalf("expected an unparseable override to leave InitialInterval at its def value, got %s", got.InitialInterval)
+	}
+}
+
+// TestRetryTracker_NextAndDue verifies that Next records increasing attempts
+// with the resolved backoff, that Due reports false until that time has
+// elapsed, and that MaxElapsedTime eventually reports exceeded.
+func TestRetryTracker_NextAndDue(t *testing.T) {
+	var tracker = NewRetryTracker()
+	var name = types.NamespacedName{Namespace: "default", Name: "dl-example"}
+	var policy = RetryPolicy{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		Multiplier:      1,
+		MaxElapsedTime:  2 * time.Millisecond,
+	}
+
+	if due, wait := tracker.Due(name); !due || wait != 0 {
+		t.Fatalf("expected a DeviceLink with no recorded state to be immediately due, got due=%v wait=%s", due, wait)
+	}
+
+	if _, _, exceeded := tracker.Next(name, policy); exceeded {
+		t.Fatalf("expected the first attempt to not exceed MaxElapsedTime")
+	}
+	if due, _ := tracker.Due(name); due {
+		t.Fatalf("expected the DeviceLink to not be due immediately after a failed attempt")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, _, exceeded := tracker.Next(name, policy); !exceeded {
+		t.Fatalf("expected MaxElapsedTime to have been exceeded by now")
+	}
+}
+
+// TestRetryTracker_Reset verifies that Reset forgets a DeviceLink's backoff
+// state so a later retry starts from the first-attempt interval again.
+func TestRetryTracker_Reset(t *testing.T) {
+	var tracker = NewRetryTracker()
+	var name = types.NamespacedName{Namespace: "default", Name: "dl-example"}
+	var policy = RetryPolicy{InitialInterval: time.Second, MaxInterval: time.Minute, Multiplier: 2}
+
+	wait1, _, _ := tracker.Next(name, policy)
+	wait2, _, _ := tracker.Next(name, policy)
+	if wait2 <= wait1 {
+		t.Fatalf("expected backoff to grow across attempts, got wait1=%s wait2=%s", wait1, wait2)
+	}
+
+	tracker.Reset(name)
+
+	wait3, _, _ := tracker.Next(name, policy)
+	if wait3 != wait1 {
+		t.Fatalf("expected Reset to restart backoff from the first attempt, got wait1=%s wait3=%s", wait1, wait3)
+	}
+}
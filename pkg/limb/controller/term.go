@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+// ErrStaleMastership is returned by TermStore.Acquire when link.Status.MasterTerm,
+// as freshly observed from the API server, has moved past the term this limb
+// previously cached, meaning another limb has since taken over the DeviceLink.
+var ErrStaleMastership = errors.New("controller: mastership term is stale")
+
+// TermStore tracks, per DeviceLink, the mastership term this limb is
+// currently operating under. A term is acquired exactly once, the first time
+// a limb observes that it owns a DeviceLink (link.Status.NodeName ==
+// r.NodeName), by CAS-incrementing link.Status.MasterTerm; it is released
+// when the limb loses ownership, so a stale session cannot be mistaken for
+// the current one after a later hand-off.
+type TermStore interface {
+	// Acquire returns the term this limb holds for the given DeviceLink,
+	// performing a CAS increment of link.Status.MasterTerm the first time the
+	// DeviceLink is seen.
+	Acquire(ctx context.Context, link *edgev1alpha1.DeviceLink) (term int64, err error)
+
+	// Current returns the in-memory term recorded for the given DeviceLink,
+	// or false if this limb has never acquired one.
+	Current(name types.NamespacedName) (term int64, ok bool)
+
+	// Release forgets the in-memory term for the given DeviceLink, e.g. once
+	// mastership has been lost or the link has been deleted.
+	Release(name types.NamespacedName)
+}
+
+// statusTermStore is a TermStore backed by the DeviceLink status subresource.
+type statusTermStore struct {
+	client.Client
+
+	mu    sync.RWMutex
+	terms map[types.NamespacedName]int64
+}
+
+// NewStatusTermStore returns a TermStore that records terms in
+// link.Status.MasterTerm via the status subresource.
+func NewStatusTermStore(c client.Client) TermStore {
+	return &statusTermStore{
+		Client: c,
+		terms:  make(map[types.NamespacedName]int64),
+	}
+}
+
+func (s *statusTermStore) Acquire(ctx context.Context, link *edgev1alpha1.DeviceLink) (int64, error) {
+	var name = types.NamespacedName{Namespace: link.Namespace, Name: link.Name}
+	var observed = link.Status.MasterTerm
+
+	if cached, ok := s.Current(name); ok {
+		switch {
+		case observed > cached:
+			// NB(thxCode) the freshly fetched link carries a term newer than
+			// the one we cached, so another limb has since won mastership of
+			// this DeviceLink (e.g. after it moved to a different node);
+			// forget our stale term rather than keep handing it out.
+			s.Release(name)
+			return 0, ErrStaleMastership
+		case observed == cached:
+			return cached, nil
+		default:
+			// observed < cached: the read is behind our own prior write
+			// (e.g. a stale informer cache); our cached term is still the
+			// one we are operating under.
+			return cached, nil
+		}
+	}
+
+	link.Status.MasterTerm++
+	if err := s.Status().Update(ctx, link); err != nil {
+		if apierrs.IsConflict(err) {
+			// NB(thxCode) another writer raced us, the caller will reconcile
+			// again against the freshly observed resource version
+			return 0, err
+		}
+		return 0, err
+	}
+
+	var term = link.Status.MasterTerm
+	s.mu.Lock()
+	s.terms[name] = term
+	s.mu.Unlock()
+	return term, nil
+}
+
+func (s *statusTermStore) Current(name types.NamespacedName) (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	term, ok := s.terms[name]
+	return term, ok
+}
+
+func (s *statusTermStore) Release(name types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.terms, name)
+}
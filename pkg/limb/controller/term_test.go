@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+func newFakeDeviceLink(name types.NamespacedName, nodeName string) *edgev1alpha1.DeviceLink {
+	return &edgev1alpha1.DeviceLink{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: name.Namespace,
+			Name:      name.Name,
+		},
+		Status: edgev1alpha1.DeviceLinkStatus{
+			NodeName: nodeName,
+		},
+	}
+}
+
+// TestStatusTermStore_ConcurrentLimbs simulates two limbs, each running its
+// own TermStore against the same underlying DeviceLink, racing to acquire
+// mastership after the DeviceLink's owning node changed. Only the limb that
+// wins the CAS increment should be able to keep operating with a term that
+// matches what is persisted; the loser must observe a conflict and must not
+// be able to pass its stale term off as current.
+func TestStatusTermStore_ConcurrentLimbs(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	if err := edgev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to register scheme: %v", err)
+	}
+
+	var name = types.NamespacedName{Namespace: "default", Name: "dl-example"}
+	var seed = newFakeDeviceLink(name, "node-a")
+	var cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(seed).Build()
+
+	var limbA = NewStatusTermStore(cl)
+	var limbB = NewStatusTermStore(cl)
+
+	var wg sync.WaitGroup
+	var termA, termB int64
+	var errA, errB error
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		var link edgev1alpha1.DeviceLink
+		if err := cl.Get(context.Background(), name, &link); err != nil {
+			errA = err
+			return
+		}
+		termA, errA = limbA.Acquire(context.Background(), &link)
+	}()
+	go func() {
+		defer wg.Done()
+		var link edgev1alpha1.DeviceLink
+		if err := cl.Get(context.Background(), name, &link); err != nil {
+			errB = err
+			return
+		}
+		termB, errB = limbB.Acquire(context.Background(), &link)
+	}()
+	wg.Wait()
+
+	// exactly one of the two concurrent acquisitions must fail with a
+	// conflict, since both started from the same resource version
+	if (errA == nil) == (errB == nil) {
+		t.Fatalf("expected exactly one acquire to conflict, got errA=%v errB=%v", errA, errB)
+	}
+
+	var winner = limbA
+	var winningTerm = termA
+	if errA != nil {
+		winner = limbB
+		winningTerm = termB
+	}
+
+	if winningTerm != 1 {
+		t.Fatalf("expected the winning limb's term to be 1, got %d", winningTerm)
+	}
+
+	if current, ok := winner.Current(name); !ok || current != winningTerm {
+		t.Fatalf("expected winner's in-memory term to be recorded as %d, got %d (ok=%v)", winningTerm, current, ok)
+	}
+
+	var persisted edgev1alpha1.DeviceLink
+	if err := cl.Get(context.Background(), name, &persisted); err != nil {
+		t.Fatalf("unable to re-fetch DeviceLink: %v", err)
+	}
+	if persisted.Status.MasterTerm != winningTerm {
+		t.Fatalf("expected persisted masterTerm to be %d, got %d", winningTerm, persisted.Status.MasterTerm)
+	}
+}
+
+// TestStatusTermStore_Acquire_DetectsStaleMastership simulates a limb that
+// acquired mastership, then a second limb (as if on a different node, with
+// its own independent TermStore) winning mastership instead. The first
+// limb's next Acquire call, now observing the bumped link.Status.MasterTerm,
+// must surface ErrStaleMastership and forget its stale in-memory term rather
+// than keep handing it out as current.
+func TestStatusTermStore_Acquire_DetectsStaleMastership(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	if err := edgev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to register scheme: %v", err)
+	}
+
+	var name = types.NamespacedName{Namespace: "default", Name: "dl-example"}
+	var seed = newFakeDeviceLink(name, "node-a")
+	var cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(seed).Build()
+
+	var limbA = NewStatusTermStore(cl)
+	var limbB = NewStatusTermStore(cl)
+
+	var link edgev1alpha1.DeviceLink
+	if err := cl.Get(context.Background(), name, &link); err != nil {
+		t.Fatalf("unable to fetch DeviceLink: %v", err)
+	}
+	termA, err := limbA.Acquire(context.Background(), &link)
+	if err != nil {
+		t.Fatalf("unexpected error acquiring initial term: %v", err)
+	}
+	if termA != 1 {
+		t.Fatalf("expected initial term to be 1, got %d", termA)
+	}
+
+	// limbB takes over, e.g. after the DeviceLink moved to its node; it
+	// starts from a fresh read and CAS-increments independently of limbA.
+	var linkForB edgev1alpha1.DeviceLink
+	if err := cl.Get(context.Background(), name, &linkForB); err != nil {
+		t.Fatalf("unable to fetch DeviceLink: %v", err)
+	}
+	termB, err := limbB.Acquire(context.Background(), &linkForB)
+	if err != nil {
+		t.Fatalf("unexpected error acquiring term for limbB: %v", err)
+	}
+	if termB != 2 {
+		t.Fatalf("expected limbB's term to be 2, got %d", termB)
+	}
+
+	// limbA reconciles again, now observing the term limbB bumped
+	var linkForA edgev1alpha1.DeviceLink
+	if err := cl.Get(context.Background(), name, &linkForA); err != nil {
+		t.Fatalf("unable to re-fetch DeviceLink: %v", err)
+	}
+	if _, err := limbA.Acquire(context.Background(), &linkForA); !errors.Is(err, ErrStaleMastership) {
+		t.Fatalf("expected limbA's re-acquire to observe ErrStaleMastership, got %v", err)
+	}
+	if _, ok := limbA.Current(name); ok {
+		t.Fatalf("expected limbA to have forgotten its stale term after ErrStaleMastership")
+	}
+}
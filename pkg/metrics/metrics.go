@@ -0,0 +1,92 @@
+// Package metrics exposes the limb's adaptor-connection Prometheus metrics.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// LimbMetricsRecorder records adaptor-connection metrics, labelled by
+// adaptor name and version so a rolling upgrade of an adaptor doesn't mix
+// the old and new version's numbers together.
+type LimbMetricsRecorder struct {
+	connections *prometheus.GaugeVec
+	connectErrs *prometheus.CounterVec
+	sendErrs    *prometheus.CounterVec
+	sendLatency *prometheus.HistogramVec
+}
+
+var (
+	recorder     *LimbMetricsRecorder
+	recorderOnce sync.Once
+)
+
+// GetLimbMetricsRecorder returns the process-wide LimbMetricsRecorder,
+// registering its collectors with the controller-runtime metrics registry
+// the first time it is called.
+func GetLimbMetricsRecorder() *LimbMetricsRecorder {
+	recorderOnce.Do(func() {
+		recorder = newLimbMetricsRecorder()
+		metrics.Registry.MustRegister(
+			recorder.connections,
+			recorder.connectErrs,
+			recorder.sendErrs,
+			recorder.sendLatency,
+		)
+	})
+	return recorder
+}
+
+func newLimbMetricsRecorder() *LimbMetricsRecorder {
+	return &LimbMetricsRecorder{
+		connections: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "octopus_limb_adaptor_connections",
+			Help: "Number of DeviceLinks currently connected to an adaptor, by adaptor name and version.",
+		}, []string{"adaptor", "version"}),
+		connectErrs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "octopus_limb_adaptor_connect_errors_total",
+			Help: "Total number of failed attempts to connect to an adaptor, by adaptor name and version.",
+		}, []string{"adaptor", "version"}),
+		sendErrs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "octopus_limb_adaptor_send_errors_total",
+			Help: "Total number of failed attempts to send data to an adaptor, by adaptor name and version.",
+		}, []string{"adaptor", "version"}),
+		sendLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "octopus_limb_adaptor_send_latency_seconds",
+			Help: "Latency of sending data to an adaptor, by adaptor name and version.",
+		}, []string{"adaptor", "version"}),
+	}
+}
+
+// IncreaseConnections increments the number of connected DeviceLinks for the
+// given adaptor name and version.
+func (r *LimbMetricsRecorder) IncreaseConnections(adaptor, version string) {
+	r.connections.WithLabelValues(adaptor, version).Inc()
+}
+
+// DecreaseConnections decrements the number of connected DeviceLinks for the
+// given adaptor name and version.
+func (r *LimbMetricsRecorder) DecreaseConnections(adaptor, version string) {
+	r.connections.WithLabelValues(adaptor, version).Dec()
+}
+
+// IncreaseConnectErrors records a failed Connect attempt for the given
+// adaptor name and version.
+func (r *LimbMetricsRecorder) IncreaseConnectErrors(adaptor, version string) {
+	r.connectErrs.WithLabelValues(adaptor, version).Inc()
+}
+
+// IncreaseSendErrors records a failed Send attempt for the given adaptor
+// name and version.
+func (r *LimbMetricsRecorder) IncreaseSendErrors(adaptor, version string) {
+	r.sendErrs.WithLabelValues(adaptor, version).Inc()
+}
+
+// ObserveSendLatency records how long a Send call to the given adaptor name
+// and version took.
+func (r *LimbMetricsRecorder) ObserveSendLatency(adaptor, version string, d time.Duration) {
+	r.sendLatency.WithLabelValues(adaptor, version).Observe(d.Seconds())
+}
@@ -0,0 +1,128 @@
+// Package devicelink provides helpers for reading and transitioning the
+// condition-based stages of a DeviceLink's status.
+package devicelink
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+const (
+	ModelExisted    edgev1alpha1.DeviceLinkConditionType = "ModelExisted"
+	AdaptorExisted  edgev1alpha1.DeviceLinkConditionType = "AdaptorExisted"
+	DeviceCreated   edgev1alpha1.DeviceLinkConditionType = "DeviceCreated"
+	DeviceConnected edgev1alpha1.DeviceLinkConditionType = "DeviceConnected"
+
+	// DeviceConnectedBackoff is reported alongside DeviceConnected == False
+	// while a DeviceLink is waiting out a retry backoff instead of being
+	// retried immediately, so anything watching for this condition type
+	// (e.g. `kubectl wait --for=condition=...`) can observe it directly
+	// instead of parsing DeviceConnected's Reason/Message.
+	DeviceConnectedBackoff edgev1alpha1.DeviceLinkConditionType = "DeviceConnectedBackoff"
+)
+
+func getStatus(status *edgev1alpha1.DeviceLinkStatus, t edgev1alpha1.DeviceLinkConditionType) metav1.ConditionStatus {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == t {
+			return status.Conditions[i].Status
+		}
+	}
+	return metav1.ConditionUnknown
+}
+
+func setCondition(status *edgev1alpha1.DeviceLinkStatus, t edgev1alpha1.DeviceLinkConditionType, cs metav1.ConditionStatus, reason, message string) {
+	var now = metav1.Now()
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == t {
+			if status.Conditions[i].Status != cs {
+				status.Conditions[i].LastTransitionTime = now
+			}
+			status.Conditions[i].Status = cs
+			status.Conditions[i].Reason = reason
+			status.Conditions[i].Message = message
+			status.Conditions[i].LastUpdateTime = now
+			return
+		}
+	}
+	status.Conditions = append(status.Conditions, edgev1alpha1.DeviceLinkCondition{
+		Type:               t,
+		Status:             cs,
+		Reason:             reason,
+		Message:            message,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+	})
+}
+
+func GetModelExistedStatus(status *edgev1alpha1.DeviceLinkStatus) metav1.ConditionStatus {
+	return getStatus(status, ModelExisted)
+}
+
+func GetAdaptorExistedStatus(status *edgev1alpha1.DeviceLinkStatus) metav1.ConditionStatus {
+	return getStatus(status, AdaptorExisted)
+}
+
+func ToCheckAdaptorExisted(status *edgev1alpha1.DeviceLinkStatus) {
+	setCondition(status, AdaptorExisted, metav1.ConditionUnknown, "Checking", "")
+}
+
+func SuccessOnAdaptorExisted(status *edgev1alpha1.DeviceLinkStatus) {
+	setCondition(status, AdaptorExisted, metav1.ConditionTrue, "Existed", "")
+}
+
+func FailOnAdaptorExisted(status *edgev1alpha1.DeviceLinkStatus, message string) {
+	setCondition(status, AdaptorExisted, metav1.ConditionFalse, "NotExisted", message)
+}
+
+func GetDeviceCreatedStatus(status *edgev1alpha1.DeviceLinkStatus) metav1.ConditionStatus {
+	return getStatus(status, DeviceCreated)
+}
+
+func ToCheckDeviceCreated(status *edgev1alpha1.DeviceLinkStatus) {
+	setCondition(status, DeviceCreated, metav1.ConditionUnknown, "Checking", "")
+}
+
+func SuccessOnDeviceCreated(status *edgev1alpha1.DeviceLinkStatus) {
+	setCondition(status, DeviceCreated, metav1.ConditionTrue, "Created", "")
+}
+
+func FailOnDeviceCreated(status *edgev1alpha1.DeviceLinkStatus, message string) {
+	setCondition(status, DeviceCreated, metav1.ConditionFalse, "FailedCreated", message)
+}
+
+func GetDeviceConnectedStatus(status *edgev1alpha1.DeviceLinkStatus) metav1.ConditionStatus {
+	return getStatus(status, DeviceConnected)
+}
+
+func SuccessOnDeviceConnected(status *edgev1alpha1.DeviceLinkStatus) {
+	setCondition(status, DeviceConnected, metav1.ConditionTrue, "Connected", "")
+}
+
+func FailOnDeviceConnected(status *edgev1alpha1.DeviceLinkStatus, message string) {
+	setCondition(status, DeviceConnected, metav1.ConditionFalse, "FailedConnected", message)
+}
+
+// GetDeviceConnectedBackoffStatus returns the DeviceConnectedBackoff condition's status.
+func GetDeviceConnectedBackoffStatus(status *edgev1alpha1.DeviceLinkStatus) metav1.ConditionStatus {
+	return getStatus(status, DeviceConnectedBackoff)
+}
+
+// ToDeviceConnectedBackoff sets DeviceConnectedBackoff to True, recording
+// that a DeviceLink is waiting out a retry backoff rather than being retried
+// immediately; nextRetryTime is surfaced in the condition message so it is
+// visible on `kubectl describe`.
+func ToDeviceConnectedBackoff(status *edgev1alpha1.DeviceLinkStatus, nextRetryTime time.Time) {
+	setCondition(status, DeviceConnectedBackoff, metav1.ConditionTrue, "Backoff", fmt.Sprintf("retrying at %s", nextRetryTime.Format(time.RFC3339)))
+}
+
+// ClearDeviceConnectedBackoff sets DeviceConnectedBackoff to False, reversing
+// ToDeviceConnectedBackoff once the adaptor connects successfully again or
+// the policy's MaxElapsedTime has been exceeded and automatic retries have
+// stopped.
+func ClearDeviceConnectedBackoff(status *edgev1alpha1.DeviceLinkStatus, reason string) {
+	setCondition(status, DeviceConnectedBackoff, metav1.ConditionFalse, reason, "")
+}
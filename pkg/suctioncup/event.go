@@ -0,0 +1,28 @@
+package suctioncup
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// EventType describes what happened to a Session.
+type EventType string
+
+const (
+	// EventConnected is fired once the adaptor accepts the session.
+	EventConnected EventType = "Connected"
+	// EventDisconnected is fired once the adaptor stream ends, either by the adaptor or by us.
+	EventDisconnected EventType = "Disconnected"
+	// EventDataReceived is fired when the adaptor pushes data back on the stream.
+	EventDataReceived EventType = "DataReceived"
+	// EventError is fired when the session goroutine observes a stream error;
+	// the session then ends and an EventDisconnected follows.
+	EventError EventType = "Error"
+)
+
+// Event is a single adaptor-initiated notification for a DeviceLink's Session.
+type Event struct {
+	NamespacedName types.NamespacedName
+	Type           EventType
+	Data           []byte
+	Err            error
+}
@@ -0,0 +1,204 @@
+package suctioncup
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"k8s.io/apimachinery/pkg/types"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+// ErrNotConnected is returned by Send when the given DeviceLink has no live
+// Session, e.g. because Connect was never called or the session has since
+// been disconnected.
+var ErrNotConnected = errors.New("suctioncup: not connected")
+
+// Dial opens the Stream a Session uses to talk to the adaptor the given
+// DeviceLink is bound to. It is supplied by whichever transport the limb is
+// built with (gRPC today), keeping this package transport-agnostic.
+type Dial func(ctx context.Context, link *edgev1alpha1.DeviceLink) (Stream, error)
+
+// SessionManager is the concrete Neurons (and Manager) implementation: it
+// keeps one long-lived Session per connected DeviceLink, keyed by
+// NamespacedName, and fans every session's ResponseEvents into a single
+// channel for the reconciler to watch, instead of the reconciler blocking
+// on the adaptor stream itself.
+type SessionManager struct {
+	dial Dial
+
+	mu       sync.Mutex
+	sessions map[types.NamespacedName]*Session
+	adaptors map[string]struct{}
+
+	events chan Event
+
+	adaptorHandlers    []AdaptorHandler
+	connectionHandlers []ConnectionHandler
+}
+
+// NewManager returns a SessionManager backed by per-DeviceLink suctioncup
+// Sessions; it implements both Neurons and Manager, so the same instance
+// can be assigned to DeviceLinkReconciler.SuctionCup and passed to
+// SetupWithManager. dial is called once per Connect to establish the
+// adaptor Stream a Session sends/receives on.
+func NewManager(dial Dial) *SessionManager {
+	return &SessionManager{
+		dial:     dial,
+		sessions: make(map[types.NamespacedName]*Session),
+		adaptors: make(map[string]struct{}),
+		events:   make(chan Event, responseEventsBufferSize),
+	}
+}
+
+// RegisterAdaptorHandler implements Manager.
+func (m *SessionManager) RegisterAdaptorHandler(handler AdaptorHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.adaptorHandlers = append(m.adaptorHandlers, handler)
+}
+
+// RegisterConnectionHandler implements Manager.
+func (m *SessionManager) RegisterConnectionHandler(handler ConnectionHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connectionHandlers = append(m.connectionHandlers, handler)
+}
+
+// RegisterAdaptor marks (name, version) as available for Connect, and
+// notifies every handler registered via RegisterAdaptorHandler.
+func (m *SessionManager) RegisterAdaptor(name, version string) {
+	m.mu.Lock()
+	m.adaptors[adaptorKey(name, version)] = struct{}{}
+	var handlers = m.adaptorHandlers
+	m.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler.OnAdaptorRegister(name)
+	}
+}
+
+// UnregisterAdaptor reverses RegisterAdaptor.
+func (m *SessionManager) UnregisterAdaptor(name, version string) {
+	m.mu.Lock()
+	delete(m.adaptors, adaptorKey(name, version))
+	var handlers = m.adaptorHandlers
+	m.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler.OnAdaptorUnregister(name)
+	}
+}
+
+func adaptorKey(name, version string) string {
+	return name + "@" + version
+}
+
+// ExistAdaptor implements Neurons.
+func (m *SessionManager) ExistAdaptor(_ context.Context, name, version string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.adaptors[adaptorKey(name, version)]
+	return ok
+}
+
+// Connect implements Neurons. It dials a fresh Stream and starts a Session
+// on it, replacing any session already held for the link; overwrite is true
+// when an existing session was replaced. A term older than the held
+// session's is rejected with ErrStaleTerm.
+func (m *SessionManager) Connect(ctx context.Context, link *edgev1alpha1.DeviceLink, term int64) (overwrite bool, err error) {
+	var name = types.NamespacedName{Namespace: link.Namespace, Name: link.Name}
+
+	stream, err := m.dial(ctx, link)
+	if err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	var previous, existed = m.sessions[name]
+	if existed && term < previous.term {
+		m.mu.Unlock()
+		_ = stream.Close()
+		return false, ErrStaleTerm
+	}
+	var session = NewSession(name, term, stream)
+	m.sessions[name] = session
+	m.mu.Unlock()
+	go m.forward(session)
+	if existed {
+		previous.Close()
+	}
+
+	var connectionHandlers = m.connectionHandlers
+	for _, handler := range connectionHandlers {
+		handler.OnConnected(name)
+	}
+	m.emit(Event{NamespacedName: name, Type: EventConnected})
+
+	return existed, nil
+}
+
+// Send implements Neurons, marshalling device to JSON before handing it to
+// the DeviceLink's Session.
+func (m *SessionManager) Send(ctx context.Context, device interface{}, link *edgev1alpha1.DeviceLink, term int64) error {
+	var name = types.NamespacedName{Namespace: link.Namespace, Name: link.Name}
+
+	m.mu.Lock()
+	var session, ok = m.sessions[name]
+	m.mu.Unlock()
+	if !ok {
+		return ErrNotConnected
+	}
+
+	data, err := jsoniter.Marshal(device)
+	if err != nil {
+		return err
+	}
+	return session.Send(ctx, term, data)
+}
+
+// Disconnect implements Neurons.
+func (m *SessionManager) Disconnect(_ context.Context, link *edgev1alpha1.DeviceLink) (existed bool) {
+	var name = types.NamespacedName{Namespace: link.Namespace, Name: link.Name}
+
+	m.mu.Lock()
+	var session, ok = m.sessions[name]
+	delete(m.sessions, name)
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	session.Close()
+
+	var connectionHandlers = m.connectionHandlers
+	for _, handler := range connectionHandlers {
+		handler.OnDisconnected(name)
+	}
+	return true
+}
+
+// ResponseEvents implements Neurons.
+func (m *SessionManager) ResponseEvents() <-chan Event {
+	return m.events
+}
+
+// forward relays a single Session's events onto the manager-wide events
+// channel until the session's own event channel closes, i.e. until the
+// session is closed.
+func (m *SessionManager) forward(session *Session) {
+	for ev := range session.ResponseEvents() {
+		m.emit(ev)
+	}
+}
+
+// emit forwards an event, dropping it instead of blocking forever if nothing
+// is currently draining ResponseEvents.
+func (m *SessionManager) emit(ev Event) {
+	select {
+	case m.events <- ev:
+	default:
+	}
+}
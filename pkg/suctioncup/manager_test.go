@@ -0,0 +1,120 @@
+package suctioncup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+// recordingStream is a Stream that records everything sent to it and lets
+// the test push data back out of Recv.
+type recordingStream struct {
+	sent   [][]byte
+	recvCh chan []byte
+	closed bool
+}
+
+func newRecordingStream() *recordingStream {
+	return &recordingStream{recvCh: make(chan []byte, 1)}
+}
+
+func (s *recordingStream) Send(_ context.Context, data []byte) error {
+	s.sent = append(s.sent, data)
+	return nil
+}
+
+func (s *recordingStream) Recv() ([]byte, error) {
+	data, ok := <-s.recvCh
+	if !ok {
+		return nil, errors.New("stream closed")
+	}
+	return data, nil
+}
+
+func (s *recordingStream) Close() error {
+	s.closed = true
+	close(s.recvCh)
+	return nil
+}
+
+func newTestLink(namespace, name string) *edgev1alpha1.DeviceLink {
+	var link edgev1alpha1.DeviceLink
+	link.Namespace = namespace
+	link.Name = name
+	return &link
+}
+
+// TestManager_ConnectSendDisconnect verifies that Connect starts a Session
+// backed by the dialed Stream, Send marshals its payload onto that Session,
+// and Disconnect tears the session down, all via the Neurons surface.
+func TestManager_ConnectSendDisconnect(t *testing.T) {
+	var stream = newRecordingStream()
+	var m = NewManager(func(ctx context.Context, link *edgev1alpha1.DeviceLink) (Stream, error) {
+		return stream, nil
+	})
+
+	var link = newTestLink("default", "dl-example")
+
+	overwrite, err := m.Connect(context.Background(), link, 1)
+	if err != nil {
+		t.Fatalf("expected Connect to succeed, got %v", err)
+	}
+	if overwrite {
+		t.Fatalf("expected the first Connect to not be an overwrite")
+	}
+
+	select {
+	case ev := <-m.ResponseEvents():
+		if ev.Type != EventConnected {
+			t.Fatalf("expected an EventConnected, got %v", ev.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Connect to emit an EventConnected promptly")
+	}
+
+	if err := m.Send(context.Background(), map[string]string{"hello": "world"}, link, 1); err != nil {
+		t.Fatalf("expected Send to succeed, got %v", err)
+	}
+	if len(stream.sent) != 1 {
+		t.Fatalf("expected one send to reach the stream, got %d", len(stream.sent))
+	}
+	if string(stream.sent[0]) != `{"hello":"world"}` {
+		t.Fatalf("expected the device to be marshalled to JSON, got %q", stream.sent[0])
+	}
+
+	if !m.Disconnect(context.Background(), link) {
+		t.Fatalf("expected Disconnect to report an existing session")
+	}
+	if !stream.closed {
+		t.Fatalf("expected Disconnect to close the underlying stream")
+	}
+
+	if err := m.Send(context.Background(), map[string]string{}, link, 1); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected after Disconnect, got %v", err)
+	}
+}
+
+// TestManager_ExistAdaptor verifies that ExistAdaptor reflects the adaptors
+// most recently registered/unregistered through RegisterAdaptor/UnregisterAdaptor.
+func TestManager_ExistAdaptor(t *testing.T) {
+	var m = NewManager(func(ctx context.Context, link *edgev1alpha1.DeviceLink) (Stream, error) {
+		return nil, errors.New("not used")
+	})
+
+	if m.ExistAdaptor(context.Background(), "modbus", "v1") {
+		t.Fatalf("expected an unregistered adaptor to not exist")
+	}
+
+	m.RegisterAdaptor("modbus", "v1")
+	if !m.ExistAdaptor(context.Background(), "modbus", "v1") {
+		t.Fatalf("expected the registered adaptor to exist")
+	}
+
+	m.UnregisterAdaptor("modbus", "v1")
+	if m.ExistAdaptor(context.Background(), "modbus", "v1") {
+		t.Fatalf("expected the unregistered adaptor to no longer exist")
+	}
+}
@@ -0,0 +1,63 @@
+package suctioncup
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	edgev1alpha1 "github.com/rancher/octopus/api/v1alpha1"
+)
+
+// Neurons defines the operations for communicating with the registered adaptors.
+// Every method accepts a context so a caller can bound how long it is willing
+// to wait on a slow or hung adaptor; implementations must propagate it down to
+// the underlying adaptor gRPC call and return promptly once it is done.
+type Neurons interface {
+	// ExistAdaptor returns true if the adaptor plugin identified by
+	// (name, version) has been registered. Several versions of the same
+	// adaptor name may be registered concurrently during a rolling upgrade,
+	// e.g. "modbus@v1" and "modbus@v2" side by side.
+	ExistAdaptor(ctx context.Context, name, version string) bool
+
+	// Connect establishes the connection of the given DeviceLink under the
+	// given mastership term, the returned "overwrite" is true if it replaces
+	// a previous connection of the same link. A term older than the one
+	// already recorded for the link is rejected, so a limb that has lost
+	// mastership cannot (re)establish a connection.
+	Connect(ctx context.Context, link *edgev1alpha1.DeviceLink, term int64) (overwrite bool, err error)
+
+	// Send delivers the given device to the adaptor which the DeviceLink is
+	// connected to, under the given mastership term. A stale term is
+	// rejected rather than forwarded to the adaptor.
+	Send(ctx context.Context, device interface{}, link *edgev1alpha1.DeviceLink, term int64) error
+
+	// Disconnect closes the connection of the given DeviceLink if existed,
+	// the returned value indicates whether a connection has been closed.
+	Disconnect(ctx context.Context, link *edgev1alpha1.DeviceLink) (existed bool)
+
+	// ResponseEvents returns the channel on which the adaptor-initiated events of
+	// every connected DeviceLink are delivered, e.g. Connected/Disconnected/DataReceived/Error.
+	ResponseEvents() <-chan Event
+}
+
+// Manager manages the lifecycle of the registered adaptors and the sessions
+// established against them.
+type Manager interface {
+	// RegisterAdaptorHandler registers the given handler to receive adaptor register/unregister callbacks.
+	RegisterAdaptorHandler(handler AdaptorHandler)
+
+	// RegisterConnectionHandler registers the given handler to receive connection callbacks.
+	RegisterConnectionHandler(handler ConnectionHandler)
+}
+
+// AdaptorHandler receives the callbacks of adaptor registering.
+type AdaptorHandler interface {
+	OnAdaptorRegister(name string)
+	OnAdaptorUnregister(name string)
+}
+
+// ConnectionHandler receives the callbacks of connection changing.
+type ConnectionHandler interface {
+	OnConnected(name types.NamespacedName)
+	OnDisconnected(name types.NamespacedName)
+}
@@ -0,0 +1,156 @@
+package suctioncup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// responseEventsBufferSize bounds the number of adaptor-initiated events that
+// can be queued for a single Session before the session goroutine blocks.
+const responseEventsBufferSize = 64
+
+// ErrStaleTerm is returned by Session.Send when called with a mastership
+// term older than the one the session was established under, so a limb that
+// has since lost mastership cannot push data through a session it no longer
+// owns.
+var ErrStaleTerm = errors.New("suctioncup: stale mastership term")
+
+// Stream abstracts the underlying adaptor gRPC stream so Session doesn't need
+// to know about the transport. Send must honour ctx cancellation/deadline and
+// return promptly once it fires, rather than blocking on the wire.
+type Stream interface {
+	Send(ctx context.Context, data []byte) error
+	Recv() ([]byte, error)
+	Close() error
+}
+
+// Session is the long-lived, per-DeviceLink handle to a connected adaptor.
+// It owns the send side of the stream, serialising Send calls and applying
+// a small backoff on transient errors, and it runs a dedicated goroutine that
+// translates adaptor stream messages into Events delivered on ResponseEvents.
+type Session struct {
+	NamespacedName types.NamespacedName
+
+	// term is the mastership term this session was established under; it is
+	// fixed for the session's lifetime; a limb that has since moved to a
+	// newer term must Close this session rather than keep sending through it.
+	term int64
+
+	stream Stream
+
+	sendMu sync.Mutex
+
+	events chan Event
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewSession creates a Session around the given stream, pinned to the given
+// mastership term, and starts its response-translating goroutine.
+func NewSession(name types.NamespacedName, term int64, stream Stream) *Session {
+	var s = &Session{
+		NamespacedName: name,
+		term:           term,
+		stream:         stream,
+		events:         make(chan Event, responseEventsBufferSize),
+		stopCh:         make(chan struct{}),
+		doneCh:         make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// ResponseEvents returns the channel of adaptor-initiated events for this session.
+func (s *Session) ResponseEvents() <-chan Event {
+	return s.events
+}
+
+// Send serialises delivery of data to the adaptor, retrying a bounded number
+// of times with a small backoff if the stream reports a transient error.
+// It gives up early, without retrying, once ctx is done. It rejects term
+// values older than the one this session was established under, so a limb
+// that has lost mastership cannot push data through a stale session.
+func (s *Session) Send(ctx context.Context, term int64, data []byte) error {
+	if term < s.term {
+		return ErrStaleTerm
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	const maxAttempts = 3
+	var backoff = 100 * time.Millisecond
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			backoff *= 2
+		}
+		if err = s.stream.Send(ctx, data); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// Close stops the session goroutine and closes the underlying stream,
+// draining the goroutine deterministically before returning.
+func (s *Session) Close() {
+	select {
+	case <-s.stopCh:
+		// already closed
+	default:
+		close(s.stopCh)
+	}
+	_ = s.stream.Close()
+	<-s.doneCh
+}
+
+// run is the dedicated per-session goroutine that turns adaptor stream
+// messages into typed Events for the controller to react to.
+func (s *Session) run() {
+	defer close(s.doneCh)
+	defer close(s.events)
+
+	for {
+		data, err := s.stream.Recv()
+		select {
+		case <-s.stopCh:
+			// emit would race the closed stopCh here; send without blocking
+			// instead, so Close cannot hang on a full buffer
+			select {
+			case s.events <- Event{NamespacedName: s.NamespacedName, Type: EventDisconnected}:
+			default:
+			}
+			return
+		default:
+		}
+
+		if err != nil {
+			s.emit(Event{NamespacedName: s.NamespacedName, Type: EventError, Err: err})
+			s.emit(Event{NamespacedName: s.NamespacedName, Type: EventDisconnected, Err: err})
+			return
+		}
+
+		s.emit(Event{NamespacedName: s.NamespacedName, Type: EventDataReceived, Data: data})
+	}
+}
+
+// emit forwards an event unless the session has already been asked to stop.
+func (s *Session) emit(ev Event) {
+	select {
+	case s.events <- ev:
+	case <-s.stopCh:
+	}
+}
@@ -0,0 +1,100 @@
+package suctioncup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// fakeStream is a Stream that never returns data, used to exercise
+// Session.Send without a real adaptor connection.
+type fakeStream struct {
+	sent    [][]byte
+	closeCh chan struct{}
+}
+
+func (f *fakeStream) Send(ctx context.Context, data []byte) error {
+	f.sent = append(f.sent, data)
+	return nil
+}
+
+func (f *fakeStream) Recv() ([]byte, error) {
+	<-f.closeCh // blocks until the test closes the session, which closes the stream
+	return nil, errors.New("stream closed")
+}
+
+func (f *fakeStream) Close() error {
+	close(f.closeCh)
+	return nil
+}
+
+// failingStream always reports a transient send error, so Send keeps
+// retrying until it runs out of attempts or ctx gives up.
+type failingStream struct {
+	closeCh chan struct{}
+}
+
+func (f *failingStream) Send(ctx context.Context, data []byte) error {
+	return errors.New("transient send failure")
+}
+
+func (f *failingStream) Recv() ([]byte, error) {
+	<-f.closeCh
+	return nil, errors.New("stream closed")
+}
+
+func (f *failingStream) Close() error {
+	close(f.closeCh)
+	return nil
+}
+
+// TestSession_Send_StaleTerm verifies that a Session establishes under term N
+// rejects a Send carrying an older term, so a limb that has lost mastership
+// cannot push data through a session it no longer owns.
+func TestSession_Send_StaleTerm(t *testing.T) {
+	var name = types.NamespacedName{Namespace: "default", Name: "dl-example"}
+	var stream = &fakeStream{closeCh: make(chan struct{})}
+	var session = NewSession(name, 2, stream)
+	defer session.Close()
+
+	if err := session.Send(context.Background(), 1, []byte("stale")); !errors.Is(err, ErrStaleTerm) {
+		t.Fatalf("expected ErrStaleTerm for a term older than the session's, got %v", err)
+	}
+	if len(stream.sent) != 0 {
+		t.Fatalf("expected no data to reach the stream for a stale term, got %d sends", len(stream.sent))
+	}
+
+	if err := session.Send(context.Background(), 2, []byte("current")); err != nil {
+		t.Fatalf("expected Send with the session's own term to succeed, got %v", err)
+	}
+	if len(stream.sent) != 1 {
+		t.Fatalf("expected one send to reach the stream, got %d", len(stream.sent))
+	}
+}
+
+// TestSession_Send_ContextCancelled verifies that Send gives up its retry
+// loop promptly once ctx is done, returning ctx.Err() instead of continuing
+// to retry against a stream that keeps failing.
+func TestSession_Send_ContextCancelled(t *testing.T) {
+	var name = types.NamespacedName{Namespace: "default", Name: "dl-example"}
+	var session = NewSession(name, 1, &failingStream{closeCh: make(chan struct{})})
+	defer session.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var done = make(chan error, 1)
+	go func() { done <- session.Send(ctx, 1, []byte("data")) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Send did not return promptly after ctx was cancelled")
+	}
+}